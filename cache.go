@@ -0,0 +1,92 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/darkfeline/saucenao/imghash"
+)
+
+// A Cache stores SearchResponses keyed by the perceptual hash of the
+// queried image (see package imghash), so that repeated queries for
+// visually identical or near-identical images can be answered without
+// spending API quota. Set Client.Cache to enable it.
+//
+// LRUCache is an in-memory reference implementation. A disk-backed
+// Cache can be implemented over any key-value store that can look
+// entries up by approximate (Hamming-distance) match on the hash.
+type Cache interface {
+	// Lookup returns a cached response for an image whose hash is
+	// within the cache's similarity threshold of hash, if one exists.
+	Lookup(hash uint64) (*SearchResponse, bool)
+	// Store saves resp under hash.
+	Store(hash uint64, resp *SearchResponse)
+}
+
+// defaultThreshold is the default maximum Hamming distance, in bits,
+// between two hashes for them to be considered a cache hit.
+const defaultThreshold = 5
+
+// LRUCache is an in-memory Cache that evicts the least recently used
+// entry once it exceeds its capacity.
+type LRUCache struct {
+	// Threshold is the maximum Hamming distance, in bits, between two
+	// hashes for them to be considered a cache hit. It defaults to 5.
+	Threshold int
+
+	mu       sync.Mutex
+	capacity int
+	entries  *list.List // of *cacheEntry
+}
+
+type cacheEntry struct {
+	hash uint64
+	resp *SearchResponse
+}
+
+// NewLRUCache returns an LRUCache that holds at most capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		Threshold: defaultThreshold,
+		capacity:  capacity,
+		entries:   list.New(),
+	}
+}
+
+// Lookup implements Cache.
+func (c *LRUCache) Lookup(hash uint64) (*SearchResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for e := c.entries.Front(); e != nil; e = e.Next() {
+		ent := e.Value.(*cacheEntry)
+		if imghash.Distance(hash, ent.hash) <= c.Threshold {
+			c.entries.MoveToFront(e)
+			return ent.resp, true
+		}
+	}
+	return nil, false
+}
+
+// Store implements Cache.
+func (c *LRUCache) Store(hash uint64, resp *SearchResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries.PushFront(&cacheEntry{hash: hash, resp: resp})
+	for c.capacity > 0 && c.entries.Len() > c.capacity {
+		c.entries.Remove(c.entries.Back())
+	}
+}
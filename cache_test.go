@@ -0,0 +1,62 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import "testing"
+
+func TestLRUCache_lookupWithinThreshold(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(2)
+	want := &SearchResponse{Header: SearchHeader{Status: 0}}
+	c.Store(0x0F, want)
+
+	// Differs by one bit, within the default threshold of 5.
+	got, ok := c.Lookup(0x0E)
+	if !ok {
+		t.Fatal("Lookup() = false, want true")
+	}
+	if got != want {
+		t.Errorf("Lookup() = %v, want %v", got, want)
+	}
+}
+
+func TestLRUCache_missBeyondThreshold(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(2)
+	c.Threshold = 1
+	c.Store(0x00, &SearchResponse{})
+
+	if _, ok := c.Lookup(0xFF); ok {
+		t.Error("Lookup() = true, want false for a hash far outside the threshold")
+	}
+}
+
+func TestLRUCache_evictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+	c := NewLRUCache(1)
+	c.Threshold = 0
+	first := &SearchResponse{Header: SearchHeader{Status: 1}}
+	second := &SearchResponse{Header: SearchHeader{Status: 2}}
+	c.Store(0x01, first)
+	c.Store(0x02, second)
+
+	if _, ok := c.Lookup(0x01); ok {
+		t.Error("Lookup(0x01) = true, want false: entry should have been evicted")
+	}
+	got, ok := c.Lookup(0x02)
+	if !ok || got != second {
+		t.Errorf("Lookup(0x02) = (%v, %v), want (%v, true)", got, ok, second)
+	}
+}
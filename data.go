@@ -28,3 +28,86 @@ type DanbooruData struct {
 	Material   string `json:"material"`
 	Creator    string `json:"creator"`
 }
+
+// GelbooruData is the result data for the Gelbooru index.
+type GelbooruData struct {
+	CommonData
+	GelbooruID int    `json:"gelbooru_id"`
+	Source     string `json:"source"`
+	Characters string `json:"characters"`
+	Material   string `json:"material"`
+	Creator    string `json:"creator"`
+}
+
+// YandereData is the result data for the Yande.re index.
+type YandereData struct {
+	CommonData
+	YandereID  int    `json:"yandere_id"`
+	Source     string `json:"source"`
+	Characters string `json:"characters"`
+	Material   string `json:"material"`
+	Creator    string `json:"creator"`
+}
+
+// KonachanData is the result data for the Konachan index.
+type KonachanData struct {
+	CommonData
+	KonachanID int    `json:"konachan_id"`
+	Source     string `json:"source"`
+	Characters string `json:"characters"`
+	Material   string `json:"material"`
+	Creator    string `json:"creator"`
+}
+
+// PixivData is the result data for the Pixiv index.
+type PixivData struct {
+	CommonData
+	Title      string `json:"title"`
+	PixivID    int    `json:"pixiv_id"`
+	MemberName string `json:"member_name"`
+	MemberID   int    `json:"member_id"`
+}
+
+// DeviantArtData is the result data for the deviantArt index.
+type DeviantArtData struct {
+	CommonData
+	Title      string `json:"title"`
+	DAID       string `json:"da_id"`
+	AuthorName string `json:"author_name"`
+	AuthorURL  string `json:"author_url"`
+}
+
+// TwitterData is the result data for the Twitter index.
+type TwitterData struct {
+	CommonData
+	TweetID           string `json:"tweet_id"`
+	CreatedAt         string `json:"created_at"`
+	TwitterUserID     string `json:"twitter_user_id"`
+	TwitterUserHandle string `json:"twitter_user_handle"`
+}
+
+// AnimeData is the result data for an anime index, e.g. the Anime,
+// H-Anime, Movies, or Shows indexes.
+type AnimeData struct {
+	CommonData
+	Source  string `json:"source"`
+	EstTime string `json:"est_time"`
+	Part    string `json:"part"`
+	Year    string `json:"year"`
+}
+
+// MangaData is the result data for a manga index, e.g. MangaDex or
+// Madokami Manga.
+type MangaData struct {
+	CommonData
+	Source string `json:"source"`
+	Part   string `json:"part"`
+}
+
+// FurAffinityData is the result data for the FurAffinity index.
+type FurAffinityData struct {
+	CommonData
+	Title      string `json:"title"`
+	Creator    string `json:"creator"`
+	CreatorURL string `json:"author_url"`
+}
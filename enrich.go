@@ -0,0 +1,60 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/darkfeline/saucenao/extractors"
+)
+
+// ExtURLs returns the result's ext_urls, common to the data of every
+// index.
+func (r *SearchResult) ExtURLs() ([]string, error) {
+	var d CommonData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result ext urls: %w", err)
+	}
+	return d.ExtURLs, nil
+}
+
+// Enrich fetches the full post record for r by trying each of r's
+// ext_urls against reg until one extractor matches, returning the
+// first successful fetch.
+func (r *SearchResult) Enrich(ctx context.Context, reg *extractors.Registry) (*extractors.Post, error) {
+	urls, err := r.ExtURLs()
+	if err != nil {
+		return nil, fmt.Errorf("search result enrich: %w", err)
+	}
+	var lastErr error
+	for _, u := range urls {
+		e, ok := reg.Find(u)
+		if !ok {
+			continue
+		}
+		post, err := e.Fetch(ctx, u)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return post, nil
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("search result enrich: %w", lastErr)
+	}
+	return nil, fmt.Errorf("search result enrich: no extractor matched any of %v", urls)
+}
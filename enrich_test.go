@@ -0,0 +1,61 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/darkfeline/saucenao/extractors"
+)
+
+type stubExtractor struct {
+	host string
+	post *extractors.Post
+}
+
+func (e *stubExtractor) Matches(url string) bool { return strings.Contains(url, e.host) }
+func (e *stubExtractor) Fetch(ctx context.Context, url string) (*extractors.Post, error) {
+	return e.post, nil
+}
+
+func TestSearchResult_Enrich(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{"ext_urls": ["https://danbooru.donmai.us/post/show/736634"]}`),
+	}
+	want := &extractors.Post{ID: "736634"}
+	reg := extractors.NewRegistry(&stubExtractor{host: "danbooru.donmai.us", post: want})
+	got, err := r.Enrich(context.Background(), reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Enrich() = %v, want %v", got, want)
+	}
+}
+
+func TestSearchResult_Enrich_noMatch(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{"ext_urls": ["https://example.com/post/1"]}`),
+	}
+	reg := extractors.NewRegistry()
+	if _, err := r.Enrich(context.Background(), reg); err == nil {
+		t.Error("Enrich() = nil error, want error when no extractor matches")
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extractors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var danbooruPostID = regexp.MustCompile(`/posts?/(?:show/)?(\d+)`)
+
+// DanbooruExtractor fetches posts from Danbooru's JSON API. It also
+// works for Danbooru-compatible instances by setting Host.
+type DanbooruExtractor struct {
+	// Host is the Danbooru instance to query. It defaults to
+	// danbooru.donmai.us.
+	Host string
+	// Client is the HTTP client used for requests. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+func (e *DanbooruExtractor) host() string {
+	if e.Host == "" {
+		return "danbooru.donmai.us"
+	}
+	return e.Host
+}
+
+// Matches implements Extractor.
+func (e *DanbooruExtractor) Matches(url string) bool {
+	return strings.Contains(url, e.host())
+}
+
+// Fetch implements Extractor.
+func (e *DanbooruExtractor) Fetch(ctx context.Context, url string) (*Post, error) {
+	m := danbooruPostID.FindStringSubmatch(url)
+	if m == nil {
+		return nil, fmt.Errorf("danbooru extractor: no post ID found in %q", url)
+	}
+	api := fmt.Sprintf("https://%s/posts/%s.json", e.host(), m[1])
+	var d struct {
+		ID              int       `json:"id"`
+		TagString       string    `json:"tag_string"`
+		TagStringArtist string    `json:"tag_string_artist"`
+		Rating          string    `json:"rating"`
+		ImageWidth      int       `json:"image_width"`
+		ImageHeight     int       `json:"image_height"`
+		FileURL         string    `json:"file_url"`
+		Source          string    `json:"source"`
+		CreatedAt       time.Time `json:"created_at"`
+	}
+	if err := getJSON(ctx, e.Client, api, &d); err != nil {
+		return nil, fmt.Errorf("danbooru extractor: fetch %q: %w", url, err)
+	}
+	return &Post{
+		ID:        strconv.Itoa(d.ID),
+		Source:    d.Source,
+		Artist:    d.TagStringArtist,
+		Tags:      strings.Fields(d.TagString),
+		Rating:    d.Rating,
+		Width:     d.ImageWidth,
+		Height:    d.ImageHeight,
+		FileURL:   d.FileURL,
+		CreatedAt: d.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package extractors enriches the sparse results returned by SauceNAO
+// with the full post record from the site the image actually lives
+// on, by scraping each site's own API from a SearchResult's ext_urls.
+package extractors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// A Post is a normalized record for an image post, as returned by an
+// Extractor.
+type Post struct {
+	ID         string
+	Source     string
+	Artist     string
+	Tags       []string
+	Rating     string
+	Width      int
+	Height     int
+	FileURL    string
+	Thumbnails []string
+	CreatedAt  time.Time
+}
+
+// An Extractor fetches a normalized Post from a post URL on a
+// particular site.
+type Extractor interface {
+	// Matches reports whether the Extractor knows how to fetch url.
+	Matches(url string) bool
+	// Fetch retrieves and normalizes the post at url.
+	Fetch(ctx context.Context, url string) (*Post, error)
+}
+
+// A Registry holds a set of Extractors and dispatches a URL to the
+// first one that matches it.
+type Registry struct {
+	extractors []Extractor
+}
+
+// NewRegistry returns a Registry containing es.
+func NewRegistry(es ...Extractor) *Registry {
+	return &Registry{extractors: append([]Extractor(nil), es...)}
+}
+
+// Register adds e to the registry.
+func (reg *Registry) Register(e Extractor) {
+	reg.extractors = append(reg.extractors, e)
+}
+
+// Find returns the first registered Extractor that matches url.
+func (reg *Registry) Find(url string) (Extractor, bool) {
+	for _, e := range reg.extractors {
+		if e.Matches(url) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// Fetch finds an Extractor matching url and uses it to fetch the
+// post.
+func (reg *Registry) Fetch(ctx context.Context, url string) (*Post, error) {
+	e, ok := reg.Find(url)
+	if !ok {
+		return nil, fmt.Errorf("extractors: fetch %q: no extractor matches", url)
+	}
+	return e.Fetch(ctx, url)
+}
+
+// getJSON performs a GET request against url and decodes the JSON
+// response body into out.
+func getJSON(ctx context.Context, c *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,263 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extractors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type stubExtractor struct {
+	host string
+	post *Post
+}
+
+func (e *stubExtractor) Matches(url string) bool { return strings.Contains(url, e.host) }
+func (e *stubExtractor) Fetch(ctx context.Context, url string) (*Post, error) {
+	return e.post, nil
+}
+
+func TestRegistry_Fetch(t *testing.T) {
+	t.Parallel()
+	want := &Post{ID: "1"}
+	reg := NewRegistry(&stubExtractor{host: "example.com", post: want})
+	got, err := reg.Fetch(context.Background(), "https://example.com/posts/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Fetch() = %v, want %v", got, want)
+	}
+}
+
+func TestRegistry_Fetch_noMatch(t *testing.T) {
+	t.Parallel()
+	reg := NewRegistry(&stubExtractor{host: "example.com"})
+	if _, err := reg.Fetch(context.Background(), "https://other.com/posts/1"); err == nil {
+		t.Error("Fetch() = nil error, want error for unmatched URL")
+	}
+}
+
+// redirectToTransport rewrites every request to target host before
+// sending it, so tests can exercise extractors that hardcode https
+// against a plain-http httptest.Server.
+type redirectToTransport struct {
+	target *url.URL
+}
+
+func (rt redirectToTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestDanbooruExtractor_Fetch(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/posts/736634.json" {
+			t.Errorf("got request path %q", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"id": 736634,
+			"tag_string": "elis konngara",
+			"tag_string_artist": "nichimatsu seri",
+			"rating": "s",
+			"image_width": 1000,
+			"image_height": 1414,
+			"file_url": "https://example.com/image.jpg",
+			"source": "http://img10.pixiv.net/img/howard19862002/12897460.jpg",
+			"created_at": "2010-01-01T00:00:00.000-08:00"
+		}`))
+	}))
+	defer srv.Close()
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &DanbooruExtractor{
+		Host:   "danbooru.donmai.us",
+		Client: &http.Client{Transport: redirectToTransport{target: target}},
+	}
+	got, err := e.Fetch(context.Background(), "https://danbooru.donmai.us/posts/736634")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "736634" || got.Artist != "nichimatsu seri" || len(got.Tags) != 2 {
+		t.Errorf("Fetch() = %+v", got)
+	}
+}
+
+func TestGelbooruExtractor_Fetch(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("id"); got != "123456" {
+			t.Errorf("got id query param %q, want 123456", got)
+		}
+		w.Write([]byte(`{
+			"post": [{
+				"id": 123456,
+				"tags": "hakurei reimu touhou",
+				"rating": "safe",
+				"width": 1000,
+				"height": 1414,
+				"file_url": "https://example.com/image.jpg",
+				"source": "https://twitter.com/example/status/1",
+				"owner": "example"
+			}]
+		}`))
+	}))
+	defer srv.Close()
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &GelbooruExtractor{
+		Host:   "gelbooru.com",
+		Client: &http.Client{Transport: redirectToTransport{target: target}},
+	}
+	got, err := e.Fetch(context.Background(), "https://gelbooru.com/index.php?page=post&s=view&id=123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "123456" || got.Artist != "example" || len(got.Tags) != 3 {
+		t.Errorf("Fetch() = %+v", got)
+	}
+}
+
+func TestYandereExtractor_Fetch(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("tags"); got != "id:654321" {
+			t.Errorf("got tags query param %q, want id:654321", got)
+		}
+		w.Write([]byte(`[{
+			"id": 654321,
+			"tags": "hakurei reimu touhou",
+			"rating": "s",
+			"width": 1000,
+			"height": 1414,
+			"file_url": "https://example.com/image.jpg",
+			"source": "https://www.pixiv.net/artworks/1",
+			"author": "example",
+			"created_at": 1262304000
+		}]`))
+	}))
+	defer srv.Close()
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &YandereExtractor{
+		Host:   "yande.re",
+		Client: &http.Client{Transport: redirectToTransport{target: target}},
+	}
+	got, err := e.Fetch(context.Background(), "https://yande.re/post/show/654321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "654321" || got.Artist != "example" || len(got.Tags) != 3 {
+		t.Errorf("Fetch() = %+v", got)
+	}
+}
+
+func TestKonachanExtractor_Fetch(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("tags"); got != "id:111222" {
+			t.Errorf("got tags query param %q, want id:111222", got)
+		}
+		w.Write([]byte(`[{
+			"id": 111222,
+			"tags": "kirisame marisa touhou",
+			"rating": "s",
+			"width": 1000,
+			"height": 1414,
+			"file_url": "https://example.com/image.jpg",
+			"source": "https://www.pixiv.net/artworks/2",
+			"author": "example",
+			"created_at": 1262304000
+		}]`))
+	}))
+	defer srv.Close()
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &KonachanExtractor{
+		Host:   "konachan.com",
+		Client: &http.Client{Transport: redirectToTransport{target: target}},
+	}
+	got, err := e.Fetch(context.Background(), "https://konachan.com/post/show/111222")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "111222" || got.Artist != "example" || len(got.Tags) != 3 {
+		t.Errorf("Fetch() = %+v", got)
+	}
+}
+
+func TestPixivExtractor_Fetch(t *testing.T) {
+	t.Parallel()
+	var gotAuth bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ajax/illust/12897460" {
+			t.Errorf("got request path %q", r.URL.Path)
+		}
+		if _, err := r.Cookie("PHPSESSID"); err == nil {
+			gotAuth = true
+		}
+		w.Write([]byte(`{
+			"error": false,
+			"message": "",
+			"body": {
+				"illustId": "12897460",
+				"userName": "example artist",
+				"tags": {"tags": [{"tag": "touhou"}, {"tag": "hakurei reimu"}]},
+				"xRestrict": 0,
+				"width": 1000,
+				"height": 1414,
+				"createDate": "2010-01-01T00:00:00+00:00",
+				"urls": {"original": "https://example.com/image.jpg"}
+			}
+		}`))
+	}))
+	defer srv.Close()
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &PixivExtractor{
+		Host:   "www.pixiv.net",
+		Client: &http.Client{Transport: redirectToTransport{target: target}},
+		Auth: func(req *http.Request) {
+			req.AddCookie(&http.Cookie{Name: "PHPSESSID", Value: "test-session"})
+		},
+	}
+	got, err := e.Fetch(context.Background(), "https://www.pixiv.net/artworks/12897460")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "12897460" || got.Artist != "example artist" || len(got.Tags) != 2 || got.Rating != "safe" {
+		t.Errorf("Fetch() = %+v", got)
+	}
+	if !gotAuth {
+		t.Error("Fetch() did not send Auth-attached credentials with the request")
+	}
+}
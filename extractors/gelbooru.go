@@ -0,0 +1,86 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extractors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var gelbooruPostID = regexp.MustCompile(`[?&]id=(\d+)`)
+
+// GelbooruExtractor fetches posts from Gelbooru's DAPI.
+type GelbooruExtractor struct {
+	// Host is the Gelbooru instance to query. It defaults to
+	// gelbooru.com.
+	Host string
+	// Client is the HTTP client used for requests. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+func (e *GelbooruExtractor) host() string {
+	if e.Host == "" {
+		return "gelbooru.com"
+	}
+	return e.Host
+}
+
+// Matches implements Extractor.
+func (e *GelbooruExtractor) Matches(url string) bool {
+	return strings.Contains(url, e.host())
+}
+
+// Fetch implements Extractor.
+func (e *GelbooruExtractor) Fetch(ctx context.Context, url string) (*Post, error) {
+	m := gelbooruPostID.FindStringSubmatch(url)
+	if m == nil {
+		return nil, fmt.Errorf("gelbooru extractor: no post ID found in %q", url)
+	}
+	api := fmt.Sprintf("https://%s/index.php?page=dapi&s=post&q=index&json=1&id=%s", e.host(), m[1])
+	var d struct {
+		Post []struct {
+			ID      int    `json:"id"`
+			Tags    string `json:"tags"`
+			Rating  string `json:"rating"`
+			Width   int    `json:"width"`
+			Height  int    `json:"height"`
+			FileURL string `json:"file_url"`
+			Source  string `json:"source"`
+			Owner   string `json:"owner"`
+		} `json:"post"`
+	}
+	if err := getJSON(ctx, e.Client, api, &d); err != nil {
+		return nil, fmt.Errorf("gelbooru extractor: fetch %q: %w", url, err)
+	}
+	if len(d.Post) == 0 {
+		return nil, fmt.Errorf("gelbooru extractor: fetch %q: post not found", url)
+	}
+	p := d.Post[0]
+	return &Post{
+		ID:      strconv.Itoa(p.ID),
+		Source:  p.Source,
+		Artist:  p.Owner,
+		Tags:    strings.Fields(p.Tags),
+		Rating:  p.Rating,
+		Width:   p.Width,
+		Height:  p.Height,
+		FileURL: p.FileURL,
+	}, nil
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extractors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var moebooruPostID = regexp.MustCompile(`/(?:post|show)/(\d+)`)
+
+// moebooruExtractor fetches posts from the Moebooru API shared by
+// Yande.re and Konachan. YandereExtractor and KonachanExtractor are
+// thin, host-specific wrappers around it.
+type moebooruExtractor struct {
+	host   string
+	client *http.Client
+}
+
+// Matches implements Extractor.
+func (e *moebooruExtractor) Matches(url string) bool {
+	return strings.Contains(url, e.host)
+}
+
+// Fetch implements Extractor.
+func (e *moebooruExtractor) Fetch(ctx context.Context, url string) (*Post, error) {
+	m := moebooruPostID.FindStringSubmatch(url)
+	if m == nil {
+		return nil, fmt.Errorf("%s extractor: no post ID found in %q", e.host, url)
+	}
+	api := fmt.Sprintf("https://%s/post.json?tags=id:%s", e.host, m[1])
+	var posts []struct {
+		ID        int    `json:"id"`
+		Tags      string `json:"tags"`
+		Rating    string `json:"rating"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		FileURL   string `json:"file_url"`
+		Source    string `json:"source"`
+		Author    string `json:"author"`
+		CreatedAt int64  `json:"created_at"`
+	}
+	if err := getJSON(ctx, e.client, api, &posts); err != nil {
+		return nil, fmt.Errorf("%s extractor: fetch %q: %w", e.host, url, err)
+	}
+	if len(posts) == 0 {
+		return nil, fmt.Errorf("%s extractor: fetch %q: post not found", e.host, url)
+	}
+	p := posts[0]
+	return &Post{
+		ID:        strconv.Itoa(p.ID),
+		Source:    p.Source,
+		Artist:    p.Author,
+		Tags:      strings.Fields(p.Tags),
+		Rating:    p.Rating,
+		Width:     p.Width,
+		Height:    p.Height,
+		FileURL:   p.FileURL,
+		CreatedAt: time.Unix(p.CreatedAt, 0),
+	}, nil
+}
+
+// YandereExtractor fetches posts from Yande.re.
+type YandereExtractor struct {
+	// Host is the Yande.re instance to query. It defaults to
+	// yande.re.
+	Host string
+	// Client is the HTTP client used for requests. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+func (e *YandereExtractor) moebooru() *moebooruExtractor {
+	host := e.Host
+	if host == "" {
+		host = "yande.re"
+	}
+	return &moebooruExtractor{host: host, client: e.Client}
+}
+
+// Matches implements Extractor.
+func (e *YandereExtractor) Matches(url string) bool { return e.moebooru().Matches(url) }
+
+// Fetch implements Extractor.
+func (e *YandereExtractor) Fetch(ctx context.Context, url string) (*Post, error) {
+	return e.moebooru().Fetch(ctx, url)
+}
+
+// KonachanExtractor fetches posts from Konachan.
+type KonachanExtractor struct {
+	// Host is the Konachan instance to query. It defaults to
+	// konachan.com.
+	Host string
+	// Client is the HTTP client used for requests. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+func (e *KonachanExtractor) moebooru() *moebooruExtractor {
+	host := e.Host
+	if host == "" {
+		host = "konachan.com"
+	}
+	return &moebooruExtractor{host: host, client: e.Client}
+}
+
+// Matches implements Extractor.
+func (e *KonachanExtractor) Matches(url string) bool { return e.moebooru().Matches(url) }
+
+// Fetch implements Extractor.
+func (e *KonachanExtractor) Fetch(ctx context.Context, url string) (*Post, error) {
+	return e.moebooru().Fetch(ctx, url)
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extractors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var pixivIllustID = regexp.MustCompile(`(?:illust_id=|/artworks/)(\d+)`)
+
+// PixivExtractor fetches posts from Pixiv's (undocumented) AJAX API.
+// Pixiv requires an authenticated session for most illustrations, so
+// callers must set Auth to attach credentials to each request.
+type PixivExtractor struct {
+	// Host is the Pixiv instance to query. It defaults to
+	// www.pixiv.net.
+	Host string
+	// Client is the HTTP client used for requests. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+	// Auth, if set, is called on each outgoing request to attach
+	// authentication, e.g. a PHPSESSID cookie obtained from a login
+	// flow or derived from a stored refresh token.
+	Auth func(req *http.Request)
+}
+
+func (e *PixivExtractor) host() string {
+	if e.Host == "" {
+		return "www.pixiv.net"
+	}
+	return e.Host
+}
+
+func (e *PixivExtractor) client() *http.Client {
+	if e.Client == nil {
+		return http.DefaultClient
+	}
+	return e.Client
+}
+
+// Matches implements Extractor.
+func (e *PixivExtractor) Matches(url string) bool {
+	return strings.Contains(url, e.host()) || strings.Contains(url, "pixiv.net")
+}
+
+// Fetch implements Extractor.
+func (e *PixivExtractor) Fetch(ctx context.Context, url string) (*Post, error) {
+	m := pixivIllustID.FindStringSubmatch(url)
+	if m == nil {
+		return nil, fmt.Errorf("pixiv extractor: no illust ID found in %q", url)
+	}
+	api := fmt.Sprintf("https://%s/ajax/illust/%s", e.host(), m[1])
+	req, err := http.NewRequestWithContext(ctx, "GET", api, nil)
+	if err != nil {
+		return nil, fmt.Errorf("pixiv extractor: fetch %q: %w", url, err)
+	}
+	if e.Auth != nil {
+		e.Auth(req)
+	}
+	resp, err := e.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("pixiv extractor: fetch %q: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("pixiv extractor: fetch %q: unexpected status %v", url, resp.Status)
+	}
+	var d struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
+		Body    struct {
+			IllustID string `json:"illustId"`
+			UserName string `json:"userName"`
+			Tags     struct {
+				Tags []struct {
+					Tag string `json:"tag"`
+				} `json:"tags"`
+			} `json:"tags"`
+			XRestrict  int       `json:"xRestrict"`
+			Width      int       `json:"width"`
+			Height     int       `json:"height"`
+			CreateDate time.Time `json:"createDate"`
+			Urls       struct {
+				Original string `json:"original"`
+			} `json:"urls"`
+		} `json:"body"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("pixiv extractor: fetch %q: %w", url, err)
+	}
+	if d.Error {
+		return nil, fmt.Errorf("pixiv extractor: fetch %q: %s", url, d.Message)
+	}
+	tags := make([]string, len(d.Body.Tags.Tags))
+	for i, t := range d.Body.Tags.Tags {
+		tags[i] = t.Tag
+	}
+	rating := "safe"
+	if d.Body.XRestrict > 0 {
+		rating = "explicit"
+	}
+	return &Post{
+		ID:        d.Body.IllustID,
+		Source:    url,
+		Artist:    d.Body.UserName,
+		Tags:      tags,
+		Rating:    rating,
+		Width:     d.Body.Width,
+		Height:    d.Body.Height,
+		FileURL:   d.Body.Urls.Original,
+		CreatedAt: d.Body.CreateDate,
+	}, nil
+}
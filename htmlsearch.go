@@ -0,0 +1,80 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/darkfeline/saucenao/internal/htmlresult"
+)
+
+// SearchHTML calls the SauceNAO search API and scrapes its HTML
+// results page, rather than requesting the JSON API (output_type=2).
+// This works without an API key and is not subject to the JSON API's
+// quota, so it is useful as a fallback; see Client.FallbackToHTML.
+//
+// The returned SearchResponse has the same shape as Search's, except
+// Header's quota fields are left zero, since the HTML page does not
+// report them.
+func (c *Client) SearchHTML(ctx context.Context, r *SearchRequest) (*SearchResponse, error) {
+	req, err := c.requestForHTMLSearch(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("saucenao search html: %w", err)
+	}
+	resp, err := c.C.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("saucenao search html: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("saucenao search html: unexpected status %v", resp.Status)
+	}
+	results, err := htmlresult.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("saucenao search html: %w", err)
+	}
+	sr := &SearchResponse{Results: make([]SearchResult, len(results))}
+	for i, res := range results {
+		data, err := json.Marshal(htmlResultData{
+			ExtURLs: res.ExtURLs,
+			Title:   res.Title,
+			Creator: res.Author,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("saucenao search html: %w", err)
+		}
+		sr.Results[i] = SearchResult{
+			Header: SearchResultHeader{
+				IndexName:  res.IndexName,
+				IndexID:    res.IndexID,
+				Thumbnail:  res.Thumbnail,
+				Similarity: res.Similarity,
+			},
+			Data: data,
+		}
+	}
+	return sr, nil
+}
+
+// htmlResultData is the Data payload synthesized for results scraped
+// from the HTML page, since it does not carry the full per-index data
+// the JSON API returns.
+type htmlResultData struct {
+	ExtURLs []string `json:"ext_urls"`
+	Title   string   `json:"title"`
+	Creator string   `json:"creator"`
+}
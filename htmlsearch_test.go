@@ -0,0 +1,157 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const htmlFixture = `<!DOCTYPE html>
+<html><body>
+<div class="result">
+  <div class="resulttitle"><div class="resulttitlebasic">Index #9: Danbooru - a.jpg</div></div>
+  <div class="resultsimilarityinfo">87.49% similarity</div>
+  <div class="resultcontentcolumn">
+    <strong>Highly Responsive to Prayers</strong><br/>
+    <a href="https://danbooru.donmai.us/post/show/736634">https://danbooru.donmai.us/post/show/736634</a>
+  </div>
+  <div class="resultcreatorinfo">Creator: nichimatsu seri</div>
+</div>
+</body></html>`
+
+type stringTransport struct {
+	body       string
+	statusCode int
+	req        *http.Request
+}
+
+func (t *stringTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.req = req
+	statusCode := t.statusCode
+	if statusCode == 0 {
+		statusCode = 200
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Body:       ioutil.NopCloser(strings.NewReader(t.body)),
+	}, nil
+}
+
+func TestClient_SearchHTML(t *testing.T) {
+	t.Parallel()
+	st := &stringTransport{body: htmlFixture}
+	c := Client{
+		C:       http.Client{Transport: st},
+		Service: "https://example.com",
+		APIKey:  "amiya",
+	}
+	got, err := c.SearchHTML(context.Background(), &SearchRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(st.req.URL.String(), "output_type=2") {
+		t.Errorf("SearchHTML request URL %q should not request the JSON API", st.req.URL)
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(got.Results))
+	}
+	r := got.Results[0]
+	if r.Header.IndexID != 9 || r.Header.IndexName != "Danbooru" {
+		t.Errorf("got header %+v", r.Header)
+	}
+	exts, err := r.ExtURLs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exts) != 1 || exts[0] != "https://danbooru.donmai.us/post/show/736634" {
+		t.Errorf("got ext_urls %v", exts)
+	}
+}
+
+func TestClient_Search_fallbackToHTML(t *testing.T) {
+	t.Parallel()
+	c := Client{
+		C:              http.Client{Transport: &fallbackTransport{htmlBody: htmlFixture}},
+		Service:        "https://example.com",
+		APIKey:         "amiya",
+		FallbackToHTML: true,
+	}
+	got, err := c.Search(context.Background(), &SearchRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(got.Results))
+	}
+}
+
+func TestClient_Search_fallbackToHTML_image(t *testing.T) {
+	t.Parallel()
+	ft := &fallbackTransport{htmlBody: htmlFixture}
+	c := Client{
+		C:              http.Client{Transport: ft},
+		Service:        "https://example.com",
+		APIKey:         "amiya",
+		FallbackToHTML: true,
+	}
+	got, err := c.Search(context.Background(), &SearchRequest{ImageBytes: strings.NewReader("FAKEIMAGEDATA")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(got.Results))
+	}
+	if len(ft.bodies) != 2 {
+		t.Fatalf("got %d requests, want 2 (JSON attempt + HTML fallback)", len(ft.bodies))
+	}
+	for i, b := range ft.bodies {
+		if !strings.Contains(b, "FAKEIMAGEDATA") {
+			t.Errorf("request %d body does not contain the original image data: %q", i, b)
+		}
+	}
+}
+
+// fallbackTransport returns 429 for the JSON API and HTML results for
+// the plain HTML endpoint, so it can exercise Client's fallback path.
+// It records each request's body so tests can check that an image
+// upload reaches both the JSON attempt and the HTML fallback intact.
+type fallbackTransport struct {
+	htmlBody string
+	bodies   []string
+}
+
+func (t *fallbackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		t.bodies = append(t.bodies, string(b))
+	}
+	if strings.Contains(req.URL.String(), "output_type=2") {
+		return &http.Response{
+			StatusCode: 429,
+			Status:     "429 Too Many Requests",
+			Body:       ioutil.NopCloser(strings.NewReader(`{"header":{"status":-2}}`)),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Body:       ioutil.NopCloser(strings.NewReader(t.htmlBody)),
+	}, nil
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imghash computes perceptual image hashes (pHash), suitable
+// for recognizing visually identical or near-identical images (e.g.
+// crops and resizes) even when their encoded bytes differ.
+package imghash
+
+import (
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// size is the side length of the grayscale image the DCT is computed
+// over.
+const size = 32
+
+// blockSize is the side length of the low-frequency DCT block used to
+// produce the hash.
+const blockSize = 8
+
+// Hash computes a 64-bit perceptual hash of the image read from r.
+// It resizes the image to 32x32 grayscale, computes its 2D DCT, and
+// thresholds the top-left 8x8 block of coefficients against the
+// median of its AC coefficients (all but the DC term) to produce one
+// bit per coefficient.
+func Hash(r io.Reader) (uint64, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return 0, err
+	}
+	gray := resizeGray(img, size, size)
+	block := lowFrequencyDCT(gray, blockSize)
+	return threshold(block), nil
+}
+
+// Distance returns the Hamming distance between two hashes, i.e. the
+// number of bits that differ. Smaller distances indicate more visually
+// similar images.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// resizeGray converts img to a w x h grayscale matrix of luminance
+// values in [0, 65535], downsampling by averaging the source pixels that
+// map to each destination pixel.
+func resizeGray(img image.Image, w, h int) [][]float64 {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	out := make([][]float64, h)
+	for y := range out {
+		out[y] = make([]float64, w)
+	}
+	for y := 0; y < h; y++ {
+		sy0 := y * sh / h
+		sy1 := (y + 1) * sh / h
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < w; x++ {
+			sx0 := x * sw / w
+			sx1 := (x + 1) * sw / w
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+			var sum float64
+			var n int
+			for sy := sy0; sy < sy1 && b.Min.Y+sy < b.Max.Y; sy++ {
+				for sx := sx0; sx < sx1 && b.Min.X+sx < b.Max.X; sx++ {
+					r, g, bl, _ := img.At(b.Min.X+sx, b.Min.Y+sy).RGBA()
+					// ITU-R BT.601 luma, on the 16-bit RGBA values.
+					lum := 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+					sum += lum
+					n++
+				}
+			}
+			if n > 0 {
+				out[y][x] = sum / float64(n)
+			}
+		}
+	}
+	return out
+}
+
+// lowFrequencyDCT computes the top-left n x n block of the 2D DCT-II
+// of gray.
+func lowFrequencyDCT(gray [][]float64, n int) [][]float64 {
+	N := len(gray)
+	block := make([][]float64, n)
+	for u := 0; u < n; u++ {
+		block[u] = make([]float64, n)
+		for v := 0; v < n; v++ {
+			var sum float64
+			for x := 0; x < N; x++ {
+				for y := 0; y < N; y++ {
+					sum += gray[x][y] *
+						math.Cos(math.Pi/float64(N)*(float64(x)+0.5)*float64(u)) *
+						math.Cos(math.Pi/float64(N)*(float64(y)+0.5)*float64(v))
+				}
+			}
+			block[u][v] = sum * alpha(u, N) * alpha(v, N)
+		}
+	}
+	return block
+}
+
+// alpha is the DCT-II normalization factor.
+func alpha(u, n int) float64 {
+	if u == 0 {
+		return math.Sqrt(1 / float64(n))
+	}
+	return math.Sqrt(2 / float64(n))
+}
+
+// threshold packs block into a 64-bit hash, with bit i set if the i'th
+// coefficient (in row-major order) is greater than the median of the
+// block's AC coefficients, i.e. excluding the DC term at [0][0].
+func threshold(block [][]float64) uint64 {
+	n := len(block)
+	ac := make([]float64, 0, n*n-1)
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			if u == 0 && v == 0 {
+				continue
+			}
+			ac = append(ac, block[u][v])
+		}
+	}
+	sort.Float64s(ac)
+	median := ac[len(ac)/2]
+
+	var hash uint64
+	i := 0
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			if block[u][v] > median {
+				hash |= 1 << uint(i)
+			}
+			i++
+		}
+	}
+	return hash
+}
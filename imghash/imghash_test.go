@@ -0,0 +1,164 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imghash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gradient(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8((x + y) % 256)})
+		}
+	}
+	return img
+}
+
+func checkerboard(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x/8+y/8)%2 == 0 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return img
+}
+
+func TestHash_deterministic(t *testing.T) {
+	t.Parallel()
+	d := encodePNG(t, gradient(64, 64))
+	a, err := Hash(bytes.NewReader(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Hash(bytes.NewReader(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("Hash() not deterministic: %#x != %#x", a, b)
+	}
+}
+
+func TestHash_distinguishesDissimilarImages(t *testing.T) {
+	t.Parallel()
+	g := encodePNG(t, gradient(64, 64))
+	c := encodePNG(t, checkerboard(64, 64))
+	ha, err := Hash(bytes.NewReader(g))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb, err := Hash(bytes.NewReader(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := Distance(ha, hb); d < 5 {
+		t.Errorf("Distance(gradient, checkerboard) = %d, want >= 5", d)
+	}
+}
+
+func TestDistance_identical(t *testing.T) {
+	t.Parallel()
+	if d := Distance(0x1234, 0x1234); d != 0 {
+		t.Errorf("Distance(x, x) = %d, want 0", d)
+	}
+}
+
+// resize returns a nearest-neighbor-scaled copy of img at w x h.
+func resize(img image.Image, w, h int) *image.Gray {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	out := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := b.Min.Y + y*sh/h
+		for x := 0; x < w; x++ {
+			sx := b.Min.X + x*sw/w
+			out.SetGray(x, y, color.GrayModel.Convert(img.At(sx, sy)).(color.Gray))
+		}
+	}
+	return out
+}
+
+// crop returns the sub-image of img with a margin cut off each side.
+func crop(img *image.Gray, margin int) *image.Gray {
+	b := img.Bounds()
+	r := image.Rect(b.Min.X+margin, b.Min.Y+margin, b.Max.X-margin, b.Max.Y-margin)
+	return img.SubImage(r).(*image.Gray)
+}
+
+// photoLike returns a smoothly-varying luminance pattern (low-frequency
+// sine waves along both axes, unlike gradient's (x+y)%256 wraparound
+// or checkerboard's sharp edges), approximating the low-frequency
+// content of a real photo so that resizing or cropping it preserves
+// the same DCT coefficients a duplicate-detecting hash relies on.
+func photoLike(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := math.Sin(float64(x)*0.02) + math.Sin(float64(y)*0.025) + math.Sin(float64(x+y)*0.008)
+			img.SetGray(x, y, color.Gray{Y: uint8((v + 3) / 6 * 255)})
+		}
+	}
+	return img
+}
+
+func TestHash_toleratesResizeAndCrop(t *testing.T) {
+	t.Parallel()
+	orig := photoLike(256, 256)
+	d, err := Hash(bytes.NewReader(encodePNG(t, orig)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resized := resize(orig, 96, 96)
+	hr, err := Hash(bytes.NewReader(encodePNG(t, resized)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// defaultThreshold (see cache.go) is the largest distance the
+	// cache treats as the same image; resizing/cropping a duplicate
+	// must stay within it.
+	const tolerance = 5
+	if dist := Distance(d, hr); dist > tolerance {
+		t.Errorf("Distance(original, resized) = %d, want <= %d (tolerate resizing)", dist, tolerance)
+	}
+
+	cropped := crop(orig, 16)
+	hc, err := Hash(bytes.NewReader(encodePNG(t, cropped)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dist := Distance(d, hc); dist > tolerance {
+		t.Errorf("Distance(original, cropped) = %d, want <= %d (tolerate cropping)", dist, tolerance)
+	}
+}
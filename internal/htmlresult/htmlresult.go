@@ -0,0 +1,200 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package htmlresult parses SauceNAO's HTML search results page, for
+// use when the JSON API (output_type=2) is unavailable.
+package htmlresult
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// A Result is one result scraped from the HTML results page.
+type Result struct {
+	IndexName  string
+	IndexID    int
+	Thumbnail  string
+	Similarity float64
+	Title      string
+	Author     string
+	ExtURLs    []string
+}
+
+var (
+	indexRE      = regexp.MustCompile(`Index #(\d+):\s*(.+?)(?:\s-\s.*)?$`)
+	similarityRE = regexp.MustCompile(`([\d.]+)\s*%`)
+)
+
+// Parse parses r as SauceNAO's HTML search results page, returning one
+// Result per "result" div.
+func Parse(r io.Reader) ([]Result, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	var results []Result
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "div" && hasClass(n, "result") {
+			results = append(results, parseResult(n))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return results, nil
+}
+
+func parseResult(n *html.Node) Result {
+	var res Result
+	if title := findFirst(n, "div", "resulttitle"); title != nil {
+		if m := indexRE.FindStringSubmatch(strings.TrimSpace(textContent(title))); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			res.IndexID = id
+			res.IndexName = strings.TrimSpace(m[2])
+		}
+	}
+	if sim := findFirst(n, "div", "resultsimilarityinfo"); sim != nil {
+		if m := similarityRE.FindStringSubmatch(textContent(sim)); m != nil {
+			res.Similarity, _ = strconv.ParseFloat(m[1], 64)
+		}
+	}
+	if img := findFirstTag(n, "img"); img != nil {
+		res.Thumbnail = attr(img, "data-src")
+		if res.Thumbnail == "" {
+			res.Thumbnail = attr(img, "src")
+		}
+	}
+	if content := findFirst(n, "div", "resultcontentcolumn"); content != nil {
+		for _, a := range findAllTag(content, "a") {
+			if href := attr(a, "href"); href != "" {
+				res.ExtURLs = append(res.ExtURLs, href)
+			}
+		}
+		if strong := findFirstTag(content, "strong"); strong != nil {
+			res.Title = strings.TrimSpace(textContent(strong))
+		}
+	}
+	if author := findFirst(n, "div", "resultcreatorinfo"); author != nil {
+		res.Author = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(textContent(author)), "Creator:"))
+	}
+	return res
+}
+
+// hasClass reports whether n has class among its space-separated
+// "class" attribute values.
+func hasClass(n *html.Node, class string) bool {
+	for _, a := range n.Attr {
+		if a.Key != "class" {
+			continue
+		}
+		for _, c := range strings.Fields(a.Val) {
+			if c == class {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// findFirst returns the first descendant of n that is a tag element
+// with the given class, or nil.
+func findFirst(n *html.Node, tag, class string) *html.Node {
+	var found *html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if found != nil {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == tag && hasClass(n, class) {
+			found = n
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return found
+}
+
+// findFirstTag returns the first descendant of n with the given tag
+// name, or nil.
+func findFirstTag(n *html.Node, tag string) *html.Node {
+	var found *html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if found != nil {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == tag {
+			found = n
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return found
+}
+
+// findAllTag returns every descendant of n with the given tag name.
+func findAllTag(n *html.Node, tag string) []*html.Node {
+	var found []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == tag {
+			found = append(found, n)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return found
+}
+
+// textContent returns the concatenated text of all descendant text
+// nodes of n.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"golang.org/x/net/html"
+
+	"github.com/darkfeline/saucenao"
+)
+
+// IndexAscii2D is the synthetic index ID Ascii2DSearcher assigns its
+// results.
+const IndexAscii2D = -2000
+
+// Ascii2DSearcher searches ascii2d.net. Unlike SauceNAO and IQDB,
+// ascii2d does not report a similarity score, so results are scored
+// by rank via RankSimilarity.
+type Ascii2DSearcher struct {
+	// Service is the ascii2d instance to query. It defaults to
+	// https://ascii2d.net.
+	Service string
+	// Client is the HTTP client used for requests. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+func (s *Ascii2DSearcher) service() string {
+	if s.Service == "" {
+		return "https://ascii2d.net"
+	}
+	return s.Service
+}
+
+func (s *Ascii2DSearcher) client() *http.Client {
+	if s.Client == nil {
+		return http.DefaultClient
+	}
+	return s.Client
+}
+
+// Search implements saucenao.Searcher. Only r.ImageBytes is
+// supported; r.URL searches are not.
+func (s *Ascii2DSearcher) Search(ctx context.Context, r *saucenao.SearchRequest) (*saucenao.SearchResponse, error) {
+	if r.ImageBytes == nil {
+		return nil, fmt.Errorf("ascii2d: search: only image searches are supported")
+	}
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	part, err := w.CreateFormFile("file", "image")
+	if err != nil {
+		return nil, fmt.Errorf("ascii2d: search: %w", err)
+	}
+	if _, err := io.Copy(part, r.ImageBytes); err != nil {
+		return nil, fmt.Errorf("ascii2d: search: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("ascii2d: search: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.service()+"/search/uploaded", &b)
+	if err != nil {
+		return nil, fmt.Errorf("ascii2d: search: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ascii2d: search: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("ascii2d: search: unexpected status %v", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ascii2d: search: %w", err)
+	}
+	sr, err := parseAscii2DResults(body)
+	if err != nil {
+		return nil, fmt.Errorf("ascii2d: search: %w", err)
+	}
+	return sr, nil
+}
+
+// parseAscii2DResults parses body as an ascii2d.net results page.
+// Each match is a "div.item-box" containing a thumbnail "img" and a
+// "a.link_to_original" pointing at the source post.
+func parseAscii2DResults(body []byte) (*saucenao.SearchResponse, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	var results []saucenao.SearchResult
+	for _, box := range findAllWithClass(doc, "div", "item-box") {
+		res, ok := parseAscii2DMatch(box, len(results))
+		if !ok {
+			continue
+		}
+		results = append(results, res)
+	}
+	return &saucenao.SearchResponse{Results: results}, nil
+}
+
+func parseAscii2DMatch(box *html.Node, rank int) (saucenao.SearchResult, bool) {
+	img := firstTag(box, "img")
+	links := findAllWithClass(box, "a", "link_to_original")
+	if img == nil || len(links) == 0 {
+		return saucenao.SearchResult{}, false
+	}
+	link := links[0]
+	thumb, href := attr(img, "src"), attr(link, "href")
+	data, _ := json.Marshal(saucenao.CommonData{ExtURLs: []string{href}})
+	return saucenao.SearchResult{
+		Header: saucenao.SearchResultHeader{
+			IndexName:  "ascii2d",
+			IndexID:    IndexAscii2D,
+			Thumbnail:  thumb,
+			Similarity: RankSimilarity(rank),
+		},
+		Data: data,
+	}, true
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/darkfeline/saucenao"
+)
+
+func TestParseAscii2DResults(t *testing.T) {
+	t.Parallel()
+	body, err := os.ReadFile("testdata/ascii2d_results.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseAscii2DResults(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	danbooru, _ := json.Marshal(saucenao.CommonData{ExtURLs: []string{"https://danbooru.donmai.us/posts/736634"}})
+	pixiv, _ := json.Marshal(saucenao.CommonData{ExtURLs: []string{"https://www.pixiv.net/artworks/12897460"}})
+	want := []saucenao.SearchResult{
+		{
+			Header: saucenao.SearchResultHeader{
+				IndexName:  "ascii2d",
+				IndexID:    IndexAscii2D,
+				Thumbnail:  "https://ascii2d.net/thumbnail/0/1/2/3.jpg",
+				Similarity: 100,
+			},
+			Data: danbooru,
+		},
+		{
+			Header: saucenao.SearchResultHeader{
+				IndexName:  "ascii2d",
+				IndexID:    IndexAscii2D,
+				Thumbnail:  "https://ascii2d.net/thumbnail/4/5/6/7.jpg",
+				Similarity: 90,
+			},
+			Data: pixiv,
+		},
+	}
+	if diff := cmp.Diff(want, got.Results); diff != "" {
+		t.Errorf("parseAscii2DResults() mismatch (-want +got):\n%s", diff)
+	}
+}
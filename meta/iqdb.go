@@ -0,0 +1,151 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+
+	"golang.org/x/net/html"
+
+	"github.com/darkfeline/saucenao"
+)
+
+// IndexIQDB is the synthetic index ID IQDBSearcher assigns its
+// results, in the namespace reserved for non-SauceNAO backends (see
+// package doc).
+const IndexIQDB = -1000
+
+// iqdbSimilarityRE extracts the percentage from a match info cell's
+// "NN% similarity" text.
+var iqdbSimilarityRE = regexp.MustCompile(`([\d.]+)\s*%\s*similarity`)
+
+// IQDBSearcher searches iqdb.org, a booru-focused reverse image
+// search engine.
+type IQDBSearcher struct {
+	// Service is the IQDB instance to query. It defaults to
+	// https://iqdb.org.
+	Service string
+	// Client is the HTTP client used for requests. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+func (s *IQDBSearcher) service() string {
+	if s.Service == "" {
+		return "https://iqdb.org"
+	}
+	return s.Service
+}
+
+func (s *IQDBSearcher) client() *http.Client {
+	if s.Client == nil {
+		return http.DefaultClient
+	}
+	return s.Client
+}
+
+// Search implements saucenao.Searcher. Only r.ImageBytes is
+// supported; r.URL searches are not.
+func (s *IQDBSearcher) Search(ctx context.Context, r *saucenao.SearchRequest) (*saucenao.SearchResponse, error) {
+	if r.ImageBytes == nil {
+		return nil, fmt.Errorf("iqdb: search: only image searches are supported")
+	}
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	part, err := w.CreateFormFile("file", "image")
+	if err != nil {
+		return nil, fmt.Errorf("iqdb: search: %w", err)
+	}
+	if _, err := io.Copy(part, r.ImageBytes); err != nil {
+		return nil, fmt.Errorf("iqdb: search: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("iqdb: search: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.service()+"/", &b)
+	if err != nil {
+		return nil, fmt.Errorf("iqdb: search: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("iqdb: search: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("iqdb: search: unexpected status %v", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("iqdb: search: %w", err)
+	}
+	sr, err := parseIQDBResults(body)
+	if err != nil {
+		return nil, fmt.Errorf("iqdb: search: %w", err)
+	}
+	return sr, nil
+}
+
+// parseIQDBResults parses body as an iqdb.org results page. Each
+// match is a "td.image" cell (the thumbnail, linked to the matching
+// post) followed by a sibling "td" holding the match's info, whose
+// text includes an "NN% similarity" figure.
+func parseIQDBResults(body []byte) (*saucenao.SearchResponse, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	var results []saucenao.SearchResult
+	for _, imageTD := range findAllWithClass(doc, "td", "image") {
+		res, ok := parseIQDBMatch(imageTD)
+		if !ok {
+			continue
+		}
+		results = append(results, res)
+	}
+	return &saucenao.SearchResponse{Results: results}, nil
+}
+
+func parseIQDBMatch(imageTD *html.Node) (saucenao.SearchResult, bool) {
+	a := firstTag(imageTD, "a")
+	img := firstTag(imageTD, "img")
+	infoTD := nextSiblingTag(imageTD, "td")
+	if a == nil || img == nil || infoTD == nil {
+		return saucenao.SearchResult{}, false
+	}
+	m := iqdbSimilarityRE.FindStringSubmatch(textContent(infoTD))
+	if m == nil {
+		return saucenao.SearchResult{}, false
+	}
+	similarity, _ := parseFloat(m[1])
+	link, thumb := attr(a, "href"), attr(img, "src")
+	data, _ := json.Marshal(saucenao.CommonData{ExtURLs: []string{link}})
+	return saucenao.SearchResult{
+		Header: saucenao.SearchResultHeader{
+			IndexName:  "IQDB",
+			IndexID:    IndexIQDB,
+			Thumbnail:  thumb,
+			Similarity: NormalizeSimilarity(similarity, 100),
+		},
+		Data: data,
+	}, true
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/darkfeline/saucenao"
+)
+
+func TestParseIQDBResults(t *testing.T) {
+	t.Parallel()
+	body, err := os.ReadFile("testdata/iqdb_results.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseIQDBResults(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	danbooru, _ := json.Marshal(saucenao.CommonData{ExtURLs: []string{"https://danbooru.donmai.us/posts/736634"}})
+	gelbooru, _ := json.Marshal(saucenao.CommonData{ExtURLs: []string{"https://gelbooru.com/index.php?page=post&s=view&id=123456"}})
+	want := []saucenao.SearchResult{
+		{
+			Header: saucenao.SearchResultHeader{
+				IndexName:  "IQDB",
+				IndexID:    IndexIQDB,
+				Thumbnail:  "//iqdb.org/thu/0123/thumb.jpg",
+				Similarity: 87.49,
+			},
+			Data: danbooru,
+		},
+		{
+			Header: saucenao.SearchResultHeader{
+				IndexName:  "IQDB",
+				IndexID:    IndexIQDB,
+				Thumbnail:  "//iqdb.org/thu/4567/thumb.jpg",
+				Similarity: 62,
+			},
+			Data: gelbooru,
+		},
+	}
+	if diff := cmp.Diff(want, got.Results); diff != "" {
+		t.Errorf("parseIQDBResults() mismatch (-want +got):\n%s", diff)
+	}
+}
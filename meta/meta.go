@@ -0,0 +1,189 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package meta aggregates a reverse image search across SauceNAO and
+// other backends (IQDB, ascii2d, TinEye), merging and deduplicating
+// their results by ext URL.
+//
+// Each non-SauceNAO backend assigns its results a synthetic,
+// negative SearchResultHeader.IndexID, reserving the positive range
+// for SauceNAO's own indexes; see IndexIQDB, IndexAscii2D and
+// IndexTinEye.
+package meta
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/darkfeline/saucenao"
+)
+
+// A Strategy controls how Aggregator reconciles results from multiple
+// backends.
+type Strategy int
+
+const (
+	// BestSimilarity queries every backend and returns all results,
+	// deduplicated by ext URL and sorted by similarity, descending.
+	BestSimilarity Strategy = iota
+	// FirstHit queries backends in order and returns the first
+	// response with at least one result, trading confidence for
+	// latency.
+	FirstHit
+	// Quorum is like BestSimilarity, but only keeps results that at
+	// least QuorumSize backends agree on (i.e. share an ext URL).
+	Quorum
+)
+
+// An Aggregator is a saucenao.Searcher that fans a search out across
+// Backends according to Strategy.
+type Aggregator struct {
+	// Backends are queried in order for FirstHit, and concurrently
+	// otherwise. Ship saucenao.Client as the reference backend.
+	Backends []saucenao.Searcher
+	// Strategy selects how results from multiple backends are
+	// reconciled. It defaults to BestSimilarity.
+	Strategy Strategy
+	// QuorumSize is the minimum number of backends whose results must
+	// agree for a merged result to be kept, used when Strategy is
+	// Quorum. It defaults to 2.
+	QuorumSize int
+}
+
+// Search implements saucenao.Searcher.
+func (a *Aggregator) Search(ctx context.Context, r *saucenao.SearchRequest) (*saucenao.SearchResponse, error) {
+	switch a.Strategy {
+	case FirstHit:
+		return a.searchFirstHit(ctx, r)
+	case Quorum:
+		return a.searchMerged(ctx, r, a.quorumSize())
+	default:
+		return a.searchMerged(ctx, r, 0)
+	}
+}
+
+func (a *Aggregator) quorumSize() int {
+	if a.QuorumSize <= 0 {
+		return 2
+	}
+	return a.QuorumSize
+}
+
+// searchFirstHit tries each backend in turn, returning the first
+// response with at least one result.
+func (a *Aggregator) searchFirstHit(ctx context.Context, r *saucenao.SearchRequest) (*saucenao.SearchResponse, error) {
+	var lastErr error
+	for _, b := range a.Backends {
+		sr, err := b.Search(ctx, r)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(sr.Results) > 0 {
+			return sr, nil
+		}
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("meta: search: %w", lastErr)
+	}
+	return &saucenao.SearchResponse{}, nil
+}
+
+// searchMerged queries every backend concurrently and merges their
+// results, deduplicating by ext URL. If minAgree > 0, only results
+// that at least that many backends agree on are kept.
+func (a *Aggregator) searchMerged(ctx context.Context, r *saucenao.SearchRequest, minAgree int) (*saucenao.SearchResponse, error) {
+	responses := a.searchAll(ctx, r)
+	merged, agreement := mergeResults(responses)
+	if minAgree > 0 {
+		filtered := merged[:0]
+		for _, res := range merged {
+			if agreement[extURLKey(res)] >= minAgree {
+				filtered = append(filtered, res)
+			}
+		}
+		merged = filtered
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Header.Similarity > merged[j].Header.Similarity
+	})
+	return &saucenao.SearchResponse{Results: merged}, nil
+}
+
+// searchAll queries every backend concurrently, ignoring backends that
+// error.
+func (a *Aggregator) searchAll(ctx context.Context, r *saucenao.SearchRequest) []*saucenao.SearchResponse {
+	responses := make([]*saucenao.SearchResponse, len(a.Backends))
+	var wg sync.WaitGroup
+	for i, b := range a.Backends {
+		i, b := i, b
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sr, err := b.Search(ctx, r); err == nil {
+				responses[i] = sr
+			}
+		}()
+	}
+	wg.Wait()
+	return responses
+}
+
+// mergeResults deduplicates results across responses by ext URL,
+// keeping the first-seen result for each URL, and returns how many
+// distinct responses each URL appeared in.
+func mergeResults(responses []*saucenao.SearchResponse) ([]saucenao.SearchResult, map[string]int) {
+	var merged []saucenao.SearchResult
+	seen := make(map[string]bool)
+	agreement := make(map[string]int)
+	for _, sr := range responses {
+		if sr == nil {
+			continue
+		}
+		counted := make(map[string]bool)
+		for _, res := range sr.Results {
+			key := extURLKey(res)
+			if key != "" && !counted[key] {
+				agreement[key]++
+				counted[key] = true
+			}
+			if key == "" || !seen[key] {
+				if key != "" {
+					seen[key] = true
+				}
+				merged = append(merged, res)
+			}
+		}
+	}
+	return merged, agreement
+}
+
+// extURLKey returns a key identifying res by its first ext URL, or ""
+// if it has none.
+func extURLKey(res saucenao.SearchResult) string {
+	urls, err := res.ExtURLs()
+	if err != nil || len(urls) == 0 {
+		return ""
+	}
+	return urls[0]
+}
+
+// parseFloat is a convenience wrapper used by the backend parsers,
+// which extract similarity values from scraped text.
+func parseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
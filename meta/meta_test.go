@@ -0,0 +1,132 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/darkfeline/saucenao"
+)
+
+type stubSearcher struct {
+	resp *saucenao.SearchResponse
+	err  error
+}
+
+func (s *stubSearcher) Search(ctx context.Context, r *saucenao.SearchRequest) (*saucenao.SearchResponse, error) {
+	return s.resp, s.err
+}
+
+func resultWithURL(url string, similarity float64) saucenao.SearchResult {
+	data, _ := json.Marshal(saucenao.CommonData{ExtURLs: []string{url}})
+	return saucenao.SearchResult{
+		Header: saucenao.SearchResultHeader{Similarity: similarity},
+		Data:   data,
+	}
+}
+
+func TestAggregator_FirstHit(t *testing.T) {
+	t.Parallel()
+	empty := &saucenao.SearchResponse{}
+	want := &saucenao.SearchResponse{Results: []saucenao.SearchResult{resultWithURL("https://a.example/1", 90)}}
+	a := &Aggregator{
+		Strategy: FirstHit,
+		Backends: []saucenao.Searcher{
+			&stubSearcher{err: fmt.Errorf("boom")},
+			&stubSearcher{resp: empty},
+			&stubSearcher{resp: want},
+		},
+	}
+	got, err := a.Search(context.Background(), &saucenao.SearchRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(got.Results))
+	}
+}
+
+func TestAggregator_BestSimilarity_dedupesAndSorts(t *testing.T) {
+	t.Parallel()
+	a := &Aggregator{
+		Strategy: BestSimilarity,
+		Backends: []saucenao.Searcher{
+			&stubSearcher{resp: &saucenao.SearchResponse{Results: []saucenao.SearchResult{
+				resultWithURL("https://a.example/1", 60),
+			}}},
+			&stubSearcher{resp: &saucenao.SearchResponse{Results: []saucenao.SearchResult{
+				resultWithURL("https://a.example/1", 60), // duplicate, should be merged
+				resultWithURL("https://b.example/2", 95),
+			}}},
+		},
+	}
+	got, err := a.Search(context.Background(), &saucenao.SearchRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 2 {
+		t.Fatalf("got %d results, want 2 (deduplicated): %+v", len(got.Results), got.Results)
+	}
+	if got.Results[0].Header.Similarity != 95 {
+		t.Errorf("got.Results[0].Header.Similarity = %v, want 95 (highest first)", got.Results[0].Header.Similarity)
+	}
+}
+
+func TestAggregator_Quorum_keepsOnlyAgreedResults(t *testing.T) {
+	t.Parallel()
+	a := &Aggregator{
+		Strategy:   Quorum,
+		QuorumSize: 2,
+		Backends: []saucenao.Searcher{
+			&stubSearcher{resp: &saucenao.SearchResponse{Results: []saucenao.SearchResult{
+				resultWithURL("https://a.example/1", 60),
+				resultWithURL("https://b.example/2", 50),
+			}}},
+			&stubSearcher{resp: &saucenao.SearchResponse{Results: []saucenao.SearchResult{
+				resultWithURL("https://a.example/1", 60),
+			}}},
+		},
+	}
+	got, err := a.Search(context.Background(), &saucenao.SearchRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("got %d results, want 1 (only the URL both backends agree on): %+v", len(got.Results), got.Results)
+	}
+}
+
+func TestNormalizeSimilarity(t *testing.T) {
+	t.Parallel()
+	if got := NormalizeSimilarity(50, 100); got != 50 {
+		t.Errorf("NormalizeSimilarity(50, 100) = %v, want 50", got)
+	}
+	if got := NormalizeSimilarity(150, 100); got != 100 {
+		t.Errorf("NormalizeSimilarity(150, 100) = %v, want 100 (clamped)", got)
+	}
+}
+
+func TestRankSimilarity(t *testing.T) {
+	t.Parallel()
+	if got := RankSimilarity(0); got != 100 {
+		t.Errorf("RankSimilarity(0) = %v, want 100", got)
+	}
+	if got := RankSimilarity(20); got != 0 {
+		t.Errorf("RankSimilarity(20) = %v, want 0 (clamped)", got)
+	}
+}
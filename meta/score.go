@@ -0,0 +1,44 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+// NormalizeSimilarity reconciles a raw similarity value reported on a
+// 0..scale range onto SauceNAO's familiar 0-100 percentage range, so
+// that results from different backends can be compared and sorted.
+func NormalizeSimilarity(value, scale float64) float64 {
+	if scale <= 0 {
+		return 0
+	}
+	pct := value / scale * 100
+	switch {
+	case pct > 100:
+		return 100
+	case pct < 0:
+		return 0
+	default:
+		return pct
+	}
+}
+
+// RankSimilarity approximates a similarity score for a backend that
+// reports only a result's rank (e.g. ascii2d, which gives no score),
+// by decaying 10 percentage points per rank starting from 100.
+func RankSimilarity(rank int) float64 {
+	pct := 100 - 10*rank
+	if pct < 0 {
+		return 0
+	}
+	return float64(pct)
+}
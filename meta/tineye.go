@@ -0,0 +1,130 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package meta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/darkfeline/saucenao"
+)
+
+// IndexTinEye is the synthetic index ID TinEyeSearcher assigns its
+// results.
+const IndexTinEye = -3000
+
+// TinEyeSearcher searches TinEye's commercial reverse image search
+// API, which requires an API key.
+type TinEyeSearcher struct {
+	// Service is the TinEye API endpoint to query. It defaults to
+	// https://api.tineye.com/rest.
+	Service string
+	APIKey  string
+	// Client is the HTTP client used for requests. It defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+func (s *TinEyeSearcher) service() string {
+	if s.Service == "" {
+		return "https://api.tineye.com/rest"
+	}
+	return s.Service
+}
+
+func (s *TinEyeSearcher) client() *http.Client {
+	if s.Client == nil {
+		return http.DefaultClient
+	}
+	return s.Client
+}
+
+// Search implements saucenao.Searcher. Only r.ImageBytes is
+// supported; r.URL searches are not.
+func (s *TinEyeSearcher) Search(ctx context.Context, r *saucenao.SearchRequest) (*saucenao.SearchResponse, error) {
+	if r.ImageBytes == nil {
+		return nil, fmt.Errorf("tineye: search: only image searches are supported")
+	}
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	part, err := w.CreateFormFile("image", "image")
+	if err != nil {
+		return nil, fmt.Errorf("tineye: search: %w", err)
+	}
+	if _, err := io.Copy(part, r.ImageBytes); err != nil {
+		return nil, fmt.Errorf("tineye: search: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("tineye: search: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.service()+"/search/?api_key="+s.APIKey, &b)
+	if err != nil {
+		return nil, fmt.Errorf("tineye: search: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tineye: search: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("tineye: search: unexpected status %v", resp.Status)
+	}
+	var tr tineyeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, fmt.Errorf("tineye: search: %w", err)
+	}
+	return tr.searchResponse(), nil
+}
+
+// tineyeResponse is the shape of a TinEye /search response.
+type tineyeResponse struct {
+	Results struct {
+		Matches []struct {
+			Score     float64 `json:"score"`
+			ImageURL  string  `json:"image_url"`
+			Backlinks []struct {
+				URL string `json:"url"`
+			} `json:"backlinks"`
+		} `json:"matches"`
+	} `json:"results"`
+}
+
+func (tr *tineyeResponse) searchResponse() *saucenao.SearchResponse {
+	matches := tr.Results.Matches
+	sr := &saucenao.SearchResponse{Results: make([]saucenao.SearchResult, len(matches))}
+	for i, m := range matches {
+		var urls []string
+		for _, bl := range m.Backlinks {
+			urls = append(urls, bl.URL)
+		}
+		data, _ := json.Marshal(saucenao.CommonData{ExtURLs: urls})
+		sr.Results[i] = saucenao.SearchResult{
+			Header: saucenao.SearchResultHeader{
+				IndexName:  "TinEye",
+				IndexID:    IndexTinEye,
+				Thumbnail:  m.ImageURL,
+				Similarity: NormalizeSimilarity(m.Score, 100),
+			},
+			Data: data,
+		}
+	}
+	return sr
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// A Limiter controls the rate at which a Client makes requests.
+// Set Client.Limiter to enable rate limiting; a Client with a nil
+// Limiter never waits or backs off, per the package's "no rate
+// limiting by default" behavior.
+type Limiter interface {
+	// Wait blocks until the Limiter permits another request to be
+	// made, or until ctx is done.
+	Wait(ctx context.Context) error
+	// Update updates the Limiter's state from a response header.
+	Update(header SearchHeader)
+}
+
+// These mirror SauceNAO's documented quota windows.
+const (
+	shortWindow = 30 * time.Second
+	longWindow  = 24 * time.Hour
+	maxBackoff  = 5 * time.Minute
+)
+
+// RateLimiter is the default Limiter implementation. It tracks the
+// short (30 second) and long (24 hour) quota buckets, refilling each
+// from the remaining counts reported in SearchHeader, and backs off
+// exponentially when a bucket is observed exhausted.
+type RateLimiter struct {
+	mu    sync.Mutex
+	short bucket
+	long  bucket
+}
+
+// NewRateLimiter returns a RateLimiter that assumes a full quota until
+// the first response is observed through Update.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		short: bucket{remaining: 1, limit: 1, window: shortWindow},
+		long:  bucket{remaining: 1, limit: 1, window: longWindow},
+	}
+}
+
+// Wait implements Limiter.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		wait := l.short.waitDuration(now)
+		if w := l.long.waitDuration(now); w > wait {
+			wait = w
+		}
+		if wait <= 0 {
+			l.short.take(now)
+			l.long.take(now)
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Update implements Limiter.
+func (l *RateLimiter) Update(header SearchHeader) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.short.observe(now, header.ShortRemaining, header.ShortLimit)
+	l.long.observe(now, header.LongRemaining, header.LongLimit)
+}
+
+// bucket tracks one of the two quota windows.
+type bucket struct {
+	remaining int
+	limit     int
+	window    time.Duration
+	updated   time.Time
+	backoff   time.Duration
+	cooldown  time.Time
+}
+
+// observe records a freshly reported remaining/limit pair, extending
+// the backoff cooldown if the bucket is exhausted.
+func (b *bucket) observe(now time.Time, remaining, limit int) {
+	if limit > 0 {
+		b.limit = limit
+	}
+	b.remaining = remaining
+	b.updated = now
+	if remaining > 0 {
+		b.backoff = 0
+		b.cooldown = time.Time{}
+		return
+	}
+	if b.backoff == 0 {
+		b.backoff = time.Second
+	} else if b.backoff *= 2; b.backoff > maxBackoff {
+		b.backoff = maxBackoff
+	}
+	b.cooldown = now.Add(b.backoff)
+}
+
+// available estimates the tokens available in the bucket at now,
+// refilling linearly over window since the last observation.
+func (b *bucket) available(now time.Time) int {
+	if b.limit <= 0 {
+		return b.remaining
+	}
+	elapsed := now.Sub(b.updated)
+	refilled := int(elapsed * time.Duration(b.limit) / b.window)
+	avail := b.remaining + refilled
+	if avail > b.limit {
+		avail = b.limit
+	}
+	return avail
+}
+
+// waitDuration returns how long to wait before the bucket has a token
+// available.
+func (b *bucket) waitDuration(now time.Time) time.Duration {
+	if now.Before(b.cooldown) {
+		return b.cooldown.Sub(now)
+	}
+	if b.available(now) > 0 {
+		return 0
+	}
+	if b.limit <= 0 || b.window <= 0 {
+		return 0
+	}
+	perToken := b.window / time.Duration(b.limit)
+	return perToken - now.Sub(b.updated)
+}
+
+// take consumes one token, assuming waitDuration returned zero.
+func (b *bucket) take(now time.Time) {
+	if avail := b.available(now); avail > 0 {
+		b.remaining = avail - 1
+	} else {
+		b.remaining = 0
+	}
+	b.updated = now
+}
+
+// SearchRetry calls Search, and if it fails because only the
+// short-term quota bucket is exhausted (the long-term bucket still has
+// quota remaining), sleeps for the duration reported by the server's
+// Retry-After header (or for the 30 second short window if that is
+// absent or zero) and retries once. Other errors, including exhaustion of the long-term
+// bucket, are returned unmodified.
+func (c *Client) SearchRetry(ctx context.Context, r *SearchRequest) (*SearchResponse, error) {
+	// Search consumes r.ImageBytes, so if we retry with the same r,
+	// buffer the image up front and rebuild a fresh reader for each
+	// attempt.
+	var imageData []byte
+	if r.ImageBytes != nil {
+		data, err := ioutil.ReadAll(r.ImageBytes)
+		if err != nil {
+			return nil, fmt.Errorf("saucenao search retry: %w", err)
+		}
+		imageData = data
+	}
+	attempt := func() (*SearchResponse, error) {
+		ar := r
+		if imageData != nil {
+			cr := *r
+			cr.ImageBytes = bytes.NewReader(imageData)
+			ar = &cr
+		}
+		return c.Search(ctx, ar)
+	}
+	resp, err := attempt()
+	if err == nil {
+		return resp, nil
+	}
+	var qerr QuotaError
+	if !errors.As(err, &qerr) {
+		return nil, err
+	}
+	if qerr.Header.ShortRemaining > 0 || qerr.Header.LongRemaining <= 0 {
+		return nil, err
+	}
+	wait := qerr.RetryAfter
+	if wait <= 0 {
+		wait = shortWindow
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return attempt()
+}
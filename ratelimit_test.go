@@ -0,0 +1,123 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait_immediateWhenQuotaAvailable(t *testing.T) {
+	t.Parallel()
+	l := NewRateLimiter()
+	l.Update(SearchHeader{
+		ShortRemaining: 5, ShortLimit: 6,
+		LongRemaining: 199, LongLimit: 200,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.Wait(ctx); err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+}
+
+func TestRateLimiter_Wait_blocksWhenExhausted(t *testing.T) {
+	t.Parallel()
+	l := NewRateLimiter()
+	l.Update(SearchHeader{
+		ShortRemaining: 0, ShortLimit: 6,
+		LongRemaining: 199, LongLimit: 200,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Error("Wait() = nil, want error from expired context")
+	}
+}
+
+func TestParseRetryAfter_seconds(t *testing.T) {
+	t.Parallel()
+	got := parseRetryAfter("30")
+	want := 30 * time.Second
+	if got != want {
+		t.Errorf("parseRetryAfter(%q) = %v, want %v", "30", got, want)
+	}
+}
+
+func TestParseRetryAfter_empty(t *testing.T) {
+	t.Parallel()
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter(\"\") = %v, want 0", got)
+	}
+}
+
+// retryOnceTransport returns a 429 exhausting only the short quota
+// bucket on the first request, then 200 on the next, so it can
+// exercise SearchRetry's retry-once path. It records each request's
+// body so tests can check an image upload survives the retry intact.
+type retryOnceTransport struct {
+	requests int
+	bodies   []string
+}
+
+func (t *retryOnceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	if req.Body != nil {
+		b, _ := ioutil.ReadAll(req.Body)
+		t.bodies = append(t.bodies, string(b))
+	}
+	if t.requests == 1 {
+		return &http.Response{
+			StatusCode: 429,
+			Status:     "429 Too Many Requests",
+			Header:     http.Header{"Retry-After": []string{"0"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"header":{"short_remaining":0,"long_remaining":100}}`)),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Body:       ioutil.NopCloser(strings.NewReader(`{"header":{"status":0},"results":[]}`)),
+	}, nil
+}
+
+func TestClient_SearchRetry_image(t *testing.T) {
+	t.Parallel()
+	rt := &retryOnceTransport{}
+	c := Client{
+		C:       http.Client{Transport: rt},
+		Service: "https://example.com",
+		APIKey:  "amiya",
+	}
+	got, err := c.SearchRetry(context.Background(), &SearchRequest{ImageBytes: strings.NewReader("FAKEIMAGEDATA")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil {
+		t.Fatal("SearchRetry() = nil response")
+	}
+	if rt.requests != 2 {
+		t.Fatalf("got %d requests, want 2 (initial attempt + retry)", rt.requests)
+	}
+	for i, b := range rt.bodies {
+		if !strings.Contains(b, "FAKEIMAGEDATA") {
+			t.Errorf("request %d body does not contain the original image data: %q", i, b)
+		}
+	}
+}
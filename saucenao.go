@@ -14,8 +14,9 @@
 
 // Package saucenao implements a SauceNAO API client.
 //
-// This package does not implement rate limiting.
-// Consider using a rate limiting package like golang.org/x/time/rate.
+// This package does not implement rate limiting by default.
+// Set Client.Limiter to opt in; RateLimiter is a ready-made
+// implementation driven by the quota fields in each SearchHeader.
 package saucenao
 
 import (
@@ -30,6 +31,9 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/darkfeline/saucenao/imghash"
 )
 
 // Client is a SauceNAO API client.
@@ -39,6 +43,19 @@ type Client struct {
 	// e.g. https://saucenao.com
 	Service string
 	APIKey  string
+	// Limiter, if set, is used by Search to wait for available quota
+	// before each request and to learn the observed quota from each
+	// response. See RateLimiter for a default implementation.
+	Limiter Limiter
+	// Cache, if set, is consulted by Search for image searches,
+	// keyed by a perceptual hash of the image. See LRUCache for a
+	// default implementation.
+	Cache Cache
+	// FallbackToHTML, if true, makes Search fall back to SearchHTML
+	// when the JSON API reports a quota error or refuses the request
+	// (status 403), so unauthenticated or over-quota callers still
+	// get results.
+	FallbackToHTML bool
 }
 
 // NewClient returns a new Client for saucenao.com.
@@ -73,21 +90,97 @@ type DBMask uint64
 
 // These are database index constants.
 const (
-	Pixiv    int = 5
-	Danbooru int = 9
-	Yandere  int = 12
-	Gelbooru int = 25
-	Konachan int = 26
-
-	PixivBit    DBMask = 1 << Pixiv
-	DanbooruBit DBMask = 1 << Danbooru
-	YandereBit  DBMask = 1 << Yandere
-	GelbooruBit DBMask = 1 << Gelbooru
-	KonachanBit DBMask = 1 << Konachan
+	Pixiv       int = 5
+	Danbooru    int = 9
+	Yandere     int = 12
+	NHentai     int = 18
+	Anime       int = 21
+	HAnime      int = 22
+	Movies      int = 23
+	Shows       int = 24
+	Gelbooru    int = 25
+	Konachan    int = 26
+	MangaDex    int = 41
+	DeviantArt  int = 34
+	FurAffinity int = 47
+	Twitter     int = 48
+
+	PixivBit       DBMask = 1 << Pixiv
+	DanbooruBit    DBMask = 1 << Danbooru
+	YandereBit     DBMask = 1 << Yandere
+	NHentaiBit     DBMask = 1 << NHentai
+	AnimeBit       DBMask = 1 << Anime
+	HAnimeBit      DBMask = 1 << HAnime
+	MoviesBit      DBMask = 1 << Movies
+	ShowsBit       DBMask = 1 << Shows
+	GelbooruBit    DBMask = 1 << Gelbooru
+	KonachanBit    DBMask = 1 << Konachan
+	MangaDexBit    DBMask = 1 << MangaDex
+	DeviantArtBit  DBMask = 1 << DeviantArt
+	FurAffinityBit DBMask = 1 << FurAffinity
+	TwitterBit     DBMask = 1 << Twitter
 )
 
 // Search calls the SauceNAO search API.
+//
+// If c.Limiter is set, Search waits on it before issuing the request
+// and reports the response's quota header to it afterward.
+//
+// If c.Cache is set and r is an image search, Search hashes the image
+// with imghash and checks the cache before spending any quota on the
+// request, storing the response in the cache on a miss.
 func (c *Client) Search(ctx context.Context, r *SearchRequest) (*SearchResponse, error) {
+	if r.ImageBytes == nil || c.Cache == nil {
+		return c.search(ctx, r)
+	}
+	hash, cr, herr := c.hashImage(r)
+	// hashImage always consumes r.ImageBytes, so use cr (whose
+	// ImageBytes holds the full original content) from here on,
+	// regardless of whether hashing succeeded.
+	r = cr
+	if herr != nil {
+		return c.search(ctx, r)
+	}
+	if resp, ok := c.Cache.Lookup(hash); ok {
+		return resp, nil
+	}
+	sr, err := c.search(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	c.Cache.Store(hash, sr)
+	return sr, nil
+}
+
+// hashImage hashes r.ImageBytes with imghash, returning a copy of r
+// whose ImageBytes is restored to its original, unconsumed content so
+// it can still be uploaded on a cache miss.
+func (c *Client) hashImage(r *SearchRequest) (hash uint64, out *SearchRequest, err error) {
+	var buf bytes.Buffer
+	hash, err = imghash.Hash(io.TeeReader(r.ImageBytes, &buf))
+	if _, cerr := io.Copy(&buf, r.ImageBytes); cerr != nil && err == nil {
+		err = cerr
+	}
+	cr := *r
+	cr.ImageBytes = &buf
+	return hash, &cr, err
+}
+
+func (c *Client) search(ctx context.Context, r *SearchRequest) (*SearchResponse, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("saucenao search: %w", err)
+		}
+	}
+	// requestForSearch consumes r.ImageBytes, so if we might fall
+	// back to SearchHTML on the same r, buffer the image as it's
+	// read the first time so it can be resent.
+	var imageBuf bytes.Buffer
+	if c.FallbackToHTML && r.ImageBytes != nil {
+		cr := *r
+		cr.ImageBytes = io.TeeReader(r.ImageBytes, &imageBuf)
+		r = &cr
+	}
 	req, err := c.requestForSearch(ctx, r)
 	if err != nil {
 		return nil, fmt.Errorf("saucenao search: %w", err)
@@ -97,28 +190,89 @@ func (c *Client) Search(ctx context.Context, r *SearchRequest) (*SearchResponse,
 		return nil, fmt.Errorf("saucenao search: %s", err)
 	}
 	defer resp.Body.Close()
+	d, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("saucenao search: %s", err)
+	}
+	fallbackToHTML := func() (*SearchResponse, bool) {
+		if !c.FallbackToHTML {
+			return nil, false
+		}
+		fr := r
+		if imageBuf.Len() > 0 {
+			cr := *r
+			cr.ImageBytes = bytes.NewReader(imageBuf.Bytes())
+			fr = &cr
+		}
+		hr, herr := c.SearchHTML(ctx, fr)
+		if herr != nil {
+			return nil, false
+		}
+		return hr, true
+	}
 	switch resp.StatusCode {
 	case 200:
 	case 429:
-		return nil, fmt.Errorf("saucenao search: %w", QuotaError{})
+		qerr := QuotaError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		var sr SearchResponse
+		if err := json.Unmarshal(d, &sr); err == nil {
+			qerr.Header = sr.Header
+		}
+		if c.Limiter != nil {
+			c.Limiter.Update(qerr.Header)
+		}
+		if hr, ok := fallbackToHTML(); ok {
+			return hr, nil
+		}
+		return nil, fmt.Errorf("saucenao search: %w", qerr)
+	case 403:
+		if hr, ok := fallbackToHTML(); ok {
+			return hr, nil
+		}
+		return nil, fmt.Errorf("saucenao search: unexpected status %v", resp.Status)
 	default:
 		return nil, fmt.Errorf("saucenao search: unexpected status %v", resp.Status)
 	}
-	d, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("saucenao search: %s", err)
-	}
 	var sr SearchResponse
 	if err := json.Unmarshal(d, &sr); err != nil {
 		return nil, fmt.Errorf("saucenao search: %s", err)
 	}
+	if c.Limiter != nil {
+		c.Limiter.Update(sr.Header)
+	}
 	return &sr, nil
 }
 
+// parseRetryAfter parses an HTTP Retry-After header value, which may
+// be given in seconds or as an HTTP date. It returns 0 if v is empty
+// or unparseable.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
 func (c *Client) requestForSearch(ctx context.Context, r *SearchRequest) (*http.Request, error) {
+	return c.newSearchRequest(ctx, r, c.searchURL(r))
+}
+
+func (c *Client) requestForHTMLSearch(ctx context.Context, r *SearchRequest) (*http.Request, error) {
+	return c.newSearchRequest(ctx, r, c.htmlSearchURL(r))
+}
+
+func (c *Client) newSearchRequest(ctx context.Context, r *SearchRequest, u string) (*http.Request, error) {
 	switch r.ImageBytes {
 	case nil:
-		req, err := http.NewRequestWithContext(ctx, "GET", c.searchURL(r), nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 		if err != nil {
 			panic(fmt.Sprintf("failed to create request: %s", err))
 		}
@@ -137,7 +291,7 @@ func (c *Client) requestForSearch(ctx context.Context, r *SearchRequest) (*http.
 		if err := w.Close(); err != nil {
 			return nil, err
 		}
-		req, err := http.NewRequestWithContext(ctx, "POST", c.searchURL(r), &b)
+		req, err := http.NewRequestWithContext(ctx, "POST", u, &b)
 		if err != nil {
 			panic(fmt.Sprintf("failed to create request: %s", err))
 		}
@@ -146,12 +300,32 @@ func (c *Client) requestForSearch(ctx context.Context, r *SearchRequest) (*http.
 	}
 }
 
-// searchURL returns the URL for performing a search request.
+// searchURL returns the URL for performing a JSON API search request.
 func (c *Client) searchURL(r *SearchRequest) string {
 	var b strings.Builder
 	b.WriteString(c.Service)
 	b.WriteString("/search.php?output_type=2&api_key=")
 	b.WriteString(c.APIKey)
+	b.WriteString(c.searchParams(r))
+	return b.String()
+}
+
+// htmlSearchURL returns the URL for performing an HTML results page
+// search request, i.e. the same request as searchURL but without
+// output_type=2.
+func (c *Client) htmlSearchURL(r *SearchRequest) string {
+	var b strings.Builder
+	b.WriteString(c.Service)
+	b.WriteString("/search.php?api_key=")
+	b.WriteString(c.APIKey)
+	b.WriteString(c.searchParams(r))
+	return b.String()
+}
+
+// searchParams returns the query parameters common to both the JSON
+// and HTML search requests.
+func (c *Client) searchParams(r *SearchRequest) string {
+	var b strings.Builder
 	b.WriteString("&numres=")
 	b.WriteString(strconv.FormatUint(uint64(r.NumRes), 10))
 	if r.TestMode {
@@ -207,6 +381,126 @@ func (r *SearchResult) AsDanbooru() (*DanbooruData, error) {
 	return &d, nil
 }
 
+// AsGelbooru returns the result data parsed for Gelbooru.
+func (r *SearchResult) AsGelbooru() (*GelbooruData, error) {
+	var d GelbooruData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as gelbooru: %w", err)
+	}
+	return &d, nil
+}
+
+// AsYandere returns the result data parsed for Yande.re.
+func (r *SearchResult) AsYandere() (*YandereData, error) {
+	var d YandereData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as yandere: %w", err)
+	}
+	return &d, nil
+}
+
+// AsKonachan returns the result data parsed for Konachan.
+func (r *SearchResult) AsKonachan() (*KonachanData, error) {
+	var d KonachanData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as konachan: %w", err)
+	}
+	return &d, nil
+}
+
+// AsPixiv returns the result data parsed for Pixiv.
+func (r *SearchResult) AsPixiv() (*PixivData, error) {
+	var d PixivData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as pixiv: %w", err)
+	}
+	return &d, nil
+}
+
+// AsDeviantArt returns the result data parsed for deviantArt.
+func (r *SearchResult) AsDeviantArt() (*DeviantArtData, error) {
+	var d DeviantArtData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as deviantart: %w", err)
+	}
+	return &d, nil
+}
+
+// AsTwitter returns the result data parsed for Twitter.
+func (r *SearchResult) AsTwitter() (*TwitterData, error) {
+	var d TwitterData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as twitter: %w", err)
+	}
+	return &d, nil
+}
+
+// AsAnime returns the result data parsed for an anime index
+// (Anime, H-Anime, Movies, or Shows).
+func (r *SearchResult) AsAnime() (*AnimeData, error) {
+	var d AnimeData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as anime: %w", err)
+	}
+	return &d, nil
+}
+
+// AsManga returns the result data parsed for a manga index
+// (MangaDex, Madokami Manga).
+func (r *SearchResult) AsManga() (*MangaData, error) {
+	var d MangaData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as manga: %w", err)
+	}
+	return &d, nil
+}
+
+// AsFurAffinity returns the result data parsed for FurAffinity.
+func (r *SearchResult) AsFurAffinity() (*FurAffinityData, error) {
+	var d FurAffinityData
+	if err := json.Unmarshal(r.Data, &d); err != nil {
+		return nil, fmt.Errorf("search result as furaffinity: %w", err)
+	}
+	return &d, nil
+}
+
+// Kind returns the index ID that produced r, for use in a type switch
+// on the value returned by Parse.
+func (r *SearchResult) Kind() int {
+	return r.Header.IndexID
+}
+
+// Parse parses r's data into the concrete type for r's index, returned
+// as an interface{}. Callers should type switch on the result, using
+// Kind to decide which case applies. If the index is not recognized,
+// Parse returns an error.
+func (r *SearchResult) Parse() (interface{}, error) {
+	switch r.Kind() {
+	case Danbooru:
+		return r.AsDanbooru()
+	case Gelbooru:
+		return r.AsGelbooru()
+	case Yandere:
+		return r.AsYandere()
+	case Konachan:
+		return r.AsKonachan()
+	case Pixiv:
+		return r.AsPixiv()
+	case DeviantArt:
+		return r.AsDeviantArt()
+	case Twitter:
+		return r.AsTwitter()
+	case Anime, HAnime, Movies, Shows:
+		return r.AsAnime()
+	case MangaDex, NHentai:
+		return r.AsManga()
+	case FurAffinity:
+		return r.AsFurAffinity()
+	default:
+		return nil, fmt.Errorf("search result parse: unrecognized index %d", r.Kind())
+	}
+}
+
 // SearchResultHeader is the header of a SearchResult.
 type SearchResultHeader struct {
 	IndexName  string  `json:"index_name"`
@@ -216,8 +510,19 @@ type SearchResultHeader struct {
 }
 
 // A QuotaError is returned when requests are rate limited.
-type QuotaError struct{}
+type QuotaError struct {
+	// RetryAfter is how long to wait before retrying, parsed from the
+	// response's Retry-After header. It is zero if the header was
+	// absent or unparseable.
+	RetryAfter time.Duration
+	// Header is the response's quota header, if the server returned
+	// one alongside the 429 status.
+	Header SearchHeader
+}
 
-func (QuotaError) Error() string {
+func (e QuotaError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited: retry after %s", e.RetryAfter)
+	}
 	return "rate limited"
 }
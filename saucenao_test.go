@@ -127,6 +127,314 @@ func TestSearchResult_AsDanbooru(t *testing.T) {
 	}
 }
 
+func TestSearchResult_AsPixiv(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "title": "あきらめてむれちゃくちゃ",
+        "pixiv_id": 70489051,
+        "member_name": "たちばな",
+        "member_id": 450014,
+        "ext_urls": [
+          "https://www.pixiv.net/member_illust.php?mode=medium&illust_id=70489051"
+        ]
+      }`),
+	}
+	got, err := r.AsPixiv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &PixivData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://www.pixiv.net/member_illust.php?mode=medium&illust_id=70489051"},
+		},
+		Title:      "あきらめてむれちゃくちゃ",
+		PixivID:    70489051,
+		MemberName: "たちばな",
+		MemberID:   450014,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_AsGelbooru(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "source": "https://twitter.com/example/status/1",
+        "characters": "hakurei reimu",
+        "material": "touhou",
+        "creator": "example artist",
+        "gelbooru_id": 123456,
+        "ext_urls": [
+          "https://gelbooru.com/index.php?page=post&s=view&id=123456"
+        ]
+      }`),
+	}
+	got, err := r.AsGelbooru()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GelbooruData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://gelbooru.com/index.php?page=post&s=view&id=123456"},
+		},
+		GelbooruID: 123456,
+		Source:     "https://twitter.com/example/status/1",
+		Characters: "hakurei reimu",
+		Material:   "touhou",
+		Creator:    "example artist",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_AsYandere(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "source": "https://www.pixiv.net/artworks/1",
+        "characters": "hakurei reimu",
+        "material": "touhou",
+        "creator": "example artist",
+        "yandere_id": 654321,
+        "ext_urls": [
+          "https://yande.re/post/show/654321"
+        ]
+      }`),
+	}
+	got, err := r.AsYandere()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &YandereData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://yande.re/post/show/654321"},
+		},
+		YandereID:  654321,
+		Source:     "https://www.pixiv.net/artworks/1",
+		Characters: "hakurei reimu",
+		Material:   "touhou",
+		Creator:    "example artist",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_AsKonachan(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "source": "https://www.pixiv.net/artworks/2",
+        "characters": "kirisame marisa",
+        "material": "touhou",
+        "creator": "example artist",
+        "konachan_id": 111222,
+        "ext_urls": [
+          "https://konachan.com/post/show/111222"
+        ]
+      }`),
+	}
+	got, err := r.AsKonachan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &KonachanData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://konachan.com/post/show/111222"},
+		},
+		KonachanID: 111222,
+		Source:     "https://www.pixiv.net/artworks/2",
+		Characters: "kirisame marisa",
+		Material:   "touhou",
+		Creator:    "example artist",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_AsDeviantArt(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "title": "Example Title",
+        "da_id": "123ABC",
+        "author_name": "exampleartist",
+        "author_url": "https://www.deviantart.com/exampleartist",
+        "ext_urls": [
+          "https://www.deviantart.com/exampleartist/art/example-123ABC"
+        ]
+      }`),
+	}
+	got, err := r.AsDeviantArt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &DeviantArtData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://www.deviantart.com/exampleartist/art/example-123ABC"},
+		},
+		Title:      "Example Title",
+		DAID:       "123ABC",
+		AuthorName: "exampleartist",
+		AuthorURL:  "https://www.deviantart.com/exampleartist",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_AsTwitter(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "tweet_id": "1234567890",
+        "created_at": "2020-01-01 00:00:00",
+        "twitter_user_id": "42",
+        "twitter_user_handle": "example",
+        "ext_urls": [
+          "https://twitter.com/example/status/1234567890"
+        ]
+      }`),
+	}
+	got, err := r.AsTwitter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &TwitterData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://twitter.com/example/status/1234567890"},
+		},
+		TweetID:           "1234567890",
+		CreatedAt:         "2020-01-01 00:00:00",
+		TwitterUserID:     "42",
+		TwitterUserHandle: "example",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_AsAnime(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "source": "Example Show",
+        "est_time": "00:01:23 / 00:24:00",
+        "part": "3",
+        "year": "2019",
+        "ext_urls": [
+          "https://www.anidb.net/anime/1"
+        ]
+      }`),
+	}
+	got, err := r.AsAnime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &AnimeData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://www.anidb.net/anime/1"},
+		},
+		Source:  "Example Show",
+		EstTime: "00:01:23 / 00:24:00",
+		Part:    "3",
+		Year:    "2019",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_AsManga(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "source": "Example Manga",
+        "part": "12",
+        "ext_urls": [
+          "https://mangadex.org/title/1"
+        ]
+      }`),
+	}
+	got, err := r.AsManga()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &MangaData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://mangadex.org/title/1"},
+		},
+		Source: "Example Manga",
+		Part:   "12",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_AsFurAffinity(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Data: json.RawMessage(`{
+        "title": "Example Title",
+        "creator": "exampleartist",
+        "author_url": "https://www.furaffinity.net/user/exampleartist/",
+        "ext_urls": [
+          "https://www.furaffinity.net/view/12345/"
+        ]
+      }`),
+	}
+	got, err := r.AsFurAffinity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &FurAffinityData{
+		CommonData: CommonData{
+			ExtURLs: []string{"https://www.furaffinity.net/view/12345/"},
+		},
+		Title:      "Example Title",
+		Creator:    "exampleartist",
+		CreatorURL: "https://www.furaffinity.net/user/exampleartist/",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("data mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchResult_Parse(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Header: SearchResultHeader{IndexID: Danbooru},
+		Data: json.RawMessage(`{
+        "danbooru_id": 736634,
+        "ext_urls": ["https://danbooru.donmai.us/post/show/736634"]
+      }`),
+	}
+	got, err := r.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.(*DanbooruData); !ok {
+		t.Errorf("Parse() returned %T, want *DanbooruData", got)
+	}
+}
+
+func TestSearchResult_Parse_unrecognized(t *testing.T) {
+	t.Parallel()
+	r := SearchResult{
+		Header: SearchResultHeader{IndexID: 9001},
+		Data:   json.RawMessage(`{}`),
+	}
+	if _, err := r.Parse(); err == nil {
+		t.Error("Parse() = nil error, want error for unrecognized index")
+	}
+}
+
 func checkRequestFile(t *testing.T, req *http.Request, want []byte) {
 	t.Helper()
 	media, params, err := mime.ParseMediaType(req.Header["Content-Type"][0])
@@ -0,0 +1,26 @@
+// Copyright (C) 2019  Allen Li
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saucenao
+
+import "context"
+
+// A Searcher performs a reverse image search. Client implements
+// Searcher; see package saucenao/meta for an Aggregator that fans a
+// search out across Client and other reverse image search backends.
+type Searcher interface {
+	Search(ctx context.Context, r *SearchRequest) (*SearchResponse, error)
+}
+
+var _ Searcher = (*Client)(nil)